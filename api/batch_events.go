@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	log "github.com/golang/glog"
+	"github.com/gorilla/mux"
+
+	"nuntius/events"
+	"nuntius/storage"
+)
+
+// maxBatchDataSize is the combined size limit across every item's data in
+// a single batch_events request.
+const maxBatchDataSize = 10 * 1000 * 1000
+
+// BatchEvents handle posting a batch of events in a single request
+type BatchEvents struct{ storage storage.Storage }
+
+// NewBatchEvents return a new BatchEvents handler
+func NewBatchEvents(storage storage.Storage) *BatchEvents {
+	return &BatchEvents{storage: storage}
+}
+
+// ServeHTTP Triggers several events in one request. Each item is published
+// independently, so a failure on one item does not fail the rest of the
+// batch.
+//
+// The body should contain a Hash with a "batch" array, each entry shaped
+// like a single PostEvents request.
+//
+// Example:
+//
+// {"batch":[{"channel":"project-3","name":"foo","data":"{\"some\":\"data\"}"}]}
+//
+// Response contains a "batch_info" array with one entry per input item,
+// holding either the requested info attributes or an "error".
+//
+// POST /apps/{app_id}/batch_events
+func (h *BatchEvents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		pathVars = mux.Vars(r)
+		appID    = pathVars["app_id"]
+	)
+
+	app, err := h.storage.GetAppByAppID(appID)
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Could not found an app with app_id: %s", appID), http.StatusBadRequest)
+		return
+	}
+
+	var input struct {
+		Batch []struct {
+			Name     string          `json:"name"`
+			Channel  string          `json:"channel"`
+			Data     json.RawMessage `json:"data"`
+			SocketID string          `json:"socket_id,omitempty"`
+			Info     string          `json:"info,omitempty"`
+		} `json:"batch"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	combinedSize := 0
+	for _, item := range input.Batch {
+		combinedSize += len(item.Data)
+	}
+
+	if combinedSize > maxBatchDataSize {
+		http.Error(w, "Request too large.", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	batchInfo := make([]map[string]interface{}, len(input.Batch))
+
+	for i, item := range input.Batch {
+		if len(item.Data) > maxDataEventSize {
+			batchInfo[i] = map[string]interface{}{"error": "Data too large"}
+			continue
+		}
+
+		channel := app.FindOrCreateChannelByChannelID(item.Channel)
+
+		if err := app.Publish(channel, events.Raw{Event: item.Name, Channel: item.Channel, Data: item.Data}, item.SocketID); err != nil {
+			log.Errorf("error publishing batch event %+v", err)
+			batchInfo[i] = map[string]interface{}{"error": "Internal Server Error"}
+			continue
+		}
+
+		info := map[string]interface{}{}
+
+		for _, a := range strings.Split(item.Info, ",") {
+			switch a {
+			case "subscription_count":
+				info["subscription_count"] = channel.TotalSubscriptions()
+			case "user_count":
+				info["user_count"] = channel.TotalUsers()
+			}
+		}
+
+		batchInfo[i] = info
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	js := map[string]interface{}{"batch": batchInfo}
+
+	if err := json.NewEncoder(w).Encode(js); err != nil {
+		log.Error(err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
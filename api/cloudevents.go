@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	log "github.com/golang/glog"
+	"github.com/gorilla/mux"
+
+	"nuntius/app"
+	"nuntius/events"
+	"nuntius/storage"
+)
+
+const (
+	cloudEventsContentType      = "application/cloudevents+json"
+	cloudEventsBatchContentType = "application/cloudevents-batch+json"
+	ceHeaderPrefix              = "Ce-"
+)
+
+// isBinaryCloudEvent reports whether header carries CloudEvents binary
+// mode attributes (ce-* headers), as opposed to a native payload.
+func isBinaryCloudEvent(header http.Header) bool {
+	for name := range header {
+		if strings.HasPrefix(name, ceHeaderPrefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CloudEvents handle ingesting CNCF CloudEvents
+type CloudEvents struct{ storage storage.Storage }
+
+// NewCloudEvents return a new CloudEvents handler
+func NewCloudEvents(storage storage.Storage) *CloudEvents {
+	return &CloudEvents{storage: storage}
+}
+
+// ServeHTTP accepts a CloudEvent in structured mode (Content-Type:
+// application/cloudevents+json, the whole body is the CE envelope),
+// binary mode (ce-* headers plus a raw body), or a batch of structured
+// CloudEvents (Content-Type: application/cloudevents-batch+json, reusing
+// the batch_events plumbing).
+//
+// The CE "subject" attribute is mapped to the channel list and "type" to
+// the Pusher event name; every other CE attribute is preserved in
+// events.Raw.Extensions, which every subscriber of the channel receives
+// under the "extensions" key alongside the event's data.
+//
+// POST /apps/{app_id}/cloudevents
+func (h *CloudEvents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		pathVars = mux.Vars(r)
+		appID    = pathVars["app_id"]
+	)
+
+	currentApp, err := h.storage.GetAppByAppID(appID)
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Could not found an app with app_id: %s", appID), http.StatusBadRequest)
+		return
+	}
+
+	serveCloudEvent(w, r, currentApp, r.Header.Get("Content-Type"))
+}
+
+// serveCloudEvent parses the request body as one or more CloudEvents,
+// according to contentType, and publishes each of them on currentApp.
+func serveCloudEvent(w http.ResponseWriter, r *http.Request, currentApp *app.App, contentType string) {
+	body, err := ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	var cloudEvents []events.CloudEvent
+
+	switch {
+	case strings.HasPrefix(contentType, cloudEventsBatchContentType):
+		var rawBatch []json.RawMessage
+
+		if err := json.Unmarshal(body, &rawBatch); err != nil {
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		parsed := make([]events.CloudEvent, 0, len(rawBatch))
+		combinedSize := 0
+
+		for _, raw := range rawBatch {
+			ce, err := events.ParseStructuredCloudEvent(raw)
+
+			if err != nil {
+				http.Error(w, "Bad request", http.StatusBadRequest)
+				return
+			}
+
+			combinedSize += len(ce.Data)
+			parsed = append(parsed, ce)
+		}
+
+		if combinedSize > maxBatchDataSize {
+			http.Error(w, "Request too large.", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		for _, ce := range parsed {
+			if len(ce.Data) > maxDataEventSize {
+				continue
+			}
+
+			cloudEvents = append(cloudEvents, ce)
+		}
+	case strings.HasPrefix(contentType, cloudEventsContentType):
+		ce, err := events.ParseStructuredCloudEvent(body)
+
+		if err != nil {
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		if len(ce.Data) > maxDataEventSize {
+			http.Error(w, "Request too large.", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		cloudEvents = append(cloudEvents, ce)
+	default:
+		ce := events.ParseBinaryCloudEvent(r.Header, body)
+
+		if len(ce.Data) > maxDataEventSize {
+			http.Error(w, "Request too large.", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		cloudEvents = append(cloudEvents, ce)
+	}
+
+	for _, ce := range cloudEvents {
+		if err := publishCloudEvent(currentApp, ce); err != nil {
+			log.Errorf("error publishing cloud event %+v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("{}")); err != nil {
+		log.Errorf("unexpected error while writing into response %+v", err)
+	}
+}
+
+// publishCloudEvent maps a CloudEvent onto nuntius's channel/event model
+// and publishes it, keeping every CE attribute as Extensions metadata.
+func publishCloudEvent(currentApp *app.App, ce events.CloudEvent) error {
+	for _, c := range strings.Split(ce.Subject, ",") {
+		c = strings.TrimSpace(c)
+
+		if c == "" {
+			continue
+		}
+
+		channel := currentApp.FindOrCreateChannelByChannelID(c)
+
+		raw := events.Raw{
+			Event:      ce.Type,
+			Channel:    c,
+			Data:       ce.Data,
+			Extensions: ce.Extensions,
+		}
+
+		if err := currentApp.Publish(channel, raw, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
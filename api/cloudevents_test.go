@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"nuntius/app"
+	"nuntius/channel"
+	"nuntius/connection"
+	"nuntius/events"
+	"nuntius/subscription"
+	"nuntius/webhooks"
+)
+
+// fakeStorage is a storage.Storage double backed by a single in-memory
+// app, with a no-op webhook retry queue.
+type fakeStorage struct{ app *app.App }
+
+func (s *fakeStorage) GetAppByAppID(appID string) (*app.App, error) { return s.app, nil }
+
+func (s *fakeStorage) Enqueue(d webhooks.PendingDelivery) error { return nil }
+
+func (s *fakeStorage) Dequeue(limit int) ([]webhooks.PendingDelivery, error) { return nil, nil }
+
+// recordingSocket is a connection.Socket that captures every written
+// frame instead of touching a real network connection.
+type recordingSocket struct {
+	written chan []byte
+}
+
+func newRecordingSocket() *recordingSocket {
+	return &recordingSocket{written: make(chan []byte, 8)}
+}
+
+func (s *recordingSocket) ReadMessage() (int, []byte, error) { select {} }
+
+func (s *recordingSocket) WriteMessage(messageType int, data []byte) error {
+	s.written <- data
+	return nil
+}
+
+func (s *recordingSocket) SetReadDeadline(time.Time) error             { return nil }
+func (s *recordingSocket) SetWriteDeadline(time.Time) error            { return nil }
+func (s *recordingSocket) SetPingHandler(h func(appData string) error) {}
+func (s *recordingSocket) SetPongHandler(h func(appData string) error) {}
+func (s *recordingSocket) Close() error                                { return nil }
+
+func subscribeRecorder(a *app.App, channelID string) (*channel.Channel, *recordingSocket) {
+	c := a.FindOrCreateChannelByChannelID(channelID)
+	socket := newRecordingSocket()
+	conn := connection.New("socket-1", socket)
+	a.Subscribe(c, subscription.New(conn, "{}"))
+
+	return c, socket
+}
+
+func postCloudEvents(t *testing.T, a *app.App, contentType string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/apps/app-id/cloudevents", bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req = mux.SetURLVars(req, map[string]string{"app_id": "app-id"})
+
+	w := httptest.NewRecorder()
+	NewCloudEvents(&fakeStorage{app: a}).ServeHTTP(w, req)
+
+	return w
+}
+
+func TestCloudEventsBatchPreservesExtensions(t *testing.T) {
+	a := app.New("app-id", "key", "secret")
+	_, socket := subscribeRecorder(a, "room")
+
+	body := []byte(`[{
+		"specversion": "1.0",
+		"type": "message",
+		"source": "/chat",
+		"id": "1",
+		"subject": "room",
+		"data": {"text": "hi"},
+		"traceparent": "00-abc-01"
+	}]`)
+
+	w := postCloudEvents(t, a, cloudEventsBatchContentType, body)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status == %d, wants %d (body: %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	select {
+	case payload := <-socket.written:
+		var raw events.Raw
+
+		if err := json.Unmarshal(payload, &raw); err != nil {
+			t.Fatalf("failed to decode published payload: %v", err)
+		}
+
+		if got := raw.Extensions["traceparent"]; got != "00-abc-01" {
+			t.Errorf("raw.Extensions[\"traceparent\"] == %v, wants %q", got, "00-abc-01")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the batch item to be published to the subscriber")
+	}
+}
+
+func TestCloudEventsBatchRejectsOverCombinedLimit(t *testing.T) {
+	a := app.New("app-id", "key", "secret")
+	subscribeRecorder(a, "room")
+
+	oversized := strings.Repeat("a", maxBatchDataSize+1)
+
+	body := []byte(`[{"specversion":"1.0","type":"message","source":"/chat","id":"1","subject":"room","data":"` + oversized + `"}]`)
+
+	w := postCloudEvents(t, a, cloudEventsBatchContentType, body)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status == %d, wants %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestCloudEventsStructuredRejectsOverPerItemLimit(t *testing.T) {
+	a := app.New("app-id", "key", "secret")
+	subscribeRecorder(a, "room")
+
+	oversized := strings.Repeat("a", maxDataEventSize+1)
+
+	body := []byte(`{"specversion":"1.0","type":"message","source":"/chat","id":"1","subject":"room","data":"` + oversized + `"}`)
+
+	w := postCloudEvents(t, a, cloudEventsContentType, body)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status == %d, wants %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
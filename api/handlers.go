@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 
 	log "github.com/golang/glog"
@@ -148,6 +149,14 @@ func (h *PostEvents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Could not found an app with app_id: %s", appID), http.StatusBadRequest)
 	}
 
+	// Producers speaking CNCF CloudEvents can publish here too, in either
+	// structured mode (whole body is the CE envelope) or binary mode
+	// (ce-* headers plus a raw body), instead of the native hash format.
+	if contentType := r.Header.Get("Content-Type"); strings.HasPrefix(contentType, cloudEventsContentType) || isBinaryCloudEvent(r.Header) {
+		serveCloudEvent(w, r, app, contentType)
+		return
+	}
+
 	var input struct {
 		Name     string          `json:"name"`
 		Data     json.RawMessage `json:"data"`
@@ -390,24 +399,37 @@ func NewGetChannelUsers(storage storage.Storage) *GetChannelUsers {
 	return &GetChannelUsers{storage: storage}
 }
 
-// ServeHTTP Allowed only for presence-channels
+// ServeHTTP Allowed only for presence-channels. Accepts optional "cursor"
+// and "limit" query parameters to page through large rosters instead of
+// returning every subscriber at once.
 //
 // Example:
 // {
 //  "users": [
 //    { "id": "1" },
 //    { "id": "2" }
-//  ]
+//  ],
+//  "next_cursor": "2"
 // }
 //
 // GET /apps/{app_id}/channels/{channel_name}/users
 func (h *GetChannelUsers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var (
 		pathVars    = mux.Vars(r)
+		queryVars   = r.URL.Query()
 		appID       = pathVars["app_id"]
 		channelName = pathVars["channel_name"]
+		cursor      = queryVars.Get("cursor")
 	)
 
+	limit := 0
+
+	if raw := queryVars.Get("limit"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil {
+			limit = parsed
+		}
+	}
+
 	isPresence := utils.IsPresenceChannel(channelName)
 
 	if !isPresence {
@@ -430,17 +452,21 @@ func (h *GetChannelUsers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := make(map[string][]interface{})
+	subs, nextCursor := channel.Page(cursor, limit)
 
 	var users []interface{}
 
-	for _, s := range channel.Subscriptions() {
+	for _, s := range subs {
 		users = append(users, struct {
 			ID string `json:"id"`
 		}{s.ID})
 	}
 
-	result["users"] = users
+	result := map[string]interface{}{"users": users}
+
+	if nextCursor != "" {
+		result["next_cursor"] = nextCursor
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(result); err != nil {
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	log "github.com/golang/glog"
+	"github.com/gorilla/mux"
+
+	"nuntius/storage"
+	"nuntius/utils"
+)
+
+// SearchChannelUsers handle autocomplete search over a presence channel's roster
+type SearchChannelUsers struct{ storage storage.Storage }
+
+// NewSearchChannelUsers return a new SearchChannelUsers handler
+func NewSearchChannelUsers(storage storage.Storage) *SearchChannelUsers {
+	return &SearchChannelUsers{storage: storage}
+}
+
+// ServeHTTP Allowed only for presence channels. Matches subscribers whose
+// user_id or any string field of their user_info starts with "query"
+// (case-insensitive), paginated via "cursor"/"limit".
+//
+// If the app has a PresenceGroupingAttribute configured (e.g. "team") and
+// a "group" query parameter is given, matches are split into "in_channel"
+// (their user_info[attribute] equals group) and "out_of_channel" (it
+// doesn't); otherwise every match is returned under "in_channel".
+//
+// Example:
+// {
+//  "in_channel": [{ "id": "1", "user_info": {"name": "Alice"} }],
+//  "out_of_channel": [],
+//  "next_cursor": "1"
+// }
+//
+// GET /apps/{app_id}/channels/{channel_name}/users/search
+func (h *SearchChannelUsers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		pathVars    = mux.Vars(r)
+		queryVars   = r.URL.Query()
+		appID       = pathVars["app_id"]
+		channelName = pathVars["channel_name"]
+		query       = queryVars.Get("query")
+		cursor      = queryVars.Get("cursor")
+		group       = queryVars.Get("group")
+	)
+
+	if !utils.IsPresenceChannel(channelName) {
+		http.Error(w, "This api endpoint is restricted to presence channels.", http.StatusBadRequest)
+		return
+	}
+
+	limit := 20
+
+	if raw := queryVars.Get("limit"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil {
+			limit = parsed
+		}
+	}
+
+	currentApp, err := h.storage.GetAppByAppID(appID)
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Could not found an app with app_id: %s", appID), http.StatusBadRequest)
+		return
+	}
+
+	channel, err := currentApp.FindChannelByChannelID(channelName)
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Could not find a channel with id %s", channelName), http.StatusBadRequest)
+		return
+	}
+
+	inChannel, outOfChannel, nextCursor := channel.SearchUsers(query, cursor, limit, currentApp.PresenceGroupingAttribute, group)
+
+	result := map[string]interface{}{
+		"in_channel":     inChannel,
+		"out_of_channel": outOfChannel,
+	}
+
+	if nextCursor != "" {
+		result["next_cursor"] = nextCursor
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Error(err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	log "github.com/golang/glog"
+	"github.com/gorilla/mux"
+
+	"nuntius/storage"
+)
+
+// Stats handle get app stats
+type Stats struct{ storage storage.Storage }
+
+// NewStats return a new Stats handler
+func NewStats(storage storage.Storage) *Stats {
+	return &Stats{storage: storage}
+}
+
+// ServeHTTP Exposes aggregate publish/delivery and ping/pong liveness
+// counters for an app, so operators can detect slow or half-open
+// connections that would otherwise silently block publishes.
+//
+// Example:
+// {
+//   "events_in": 120,
+//   "events_out": 118,
+//   "drops": 2,
+//   "pings_out": 40,
+//   "pongs_in": 38
+// }
+//
+// GET /apps/{app_id}/stats
+func (h *Stats) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		pathVars = mux.Vars(r)
+		appID    = pathVars["app_id"]
+	)
+
+	app, err := h.storage.GetAppByAppID(appID)
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Could not found an app with app_id: %s", appID), http.StatusBadRequest)
+		return
+	}
+
+	eventsIn, eventsOut, drops, pingsOut, pongsIn := app.Stats()
+
+	dto := struct {
+		EventsIn  uint64 `json:"events_in"`
+		EventsOut uint64 `json:"events_out"`
+		Drops     uint64 `json:"drops"`
+		PingsOut  uint64 `json:"pings_out"`
+		PongsIn   uint64 `json:"pongs_in"`
+	}{eventsIn, eventsOut, drops, pingsOut, pongsIn}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(dto); err != nil {
+		log.Error(err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	log "github.com/golang/glog"
+	"github.com/gorilla/mux"
+
+	"nuntius/storage"
+)
+
+// WebhookDeliveries handle inspecting recent outgoing webhook attempts
+type WebhookDeliveries struct{ storage storage.Storage }
+
+// NewWebhookDeliveries return a new WebhookDeliveries handler
+func NewWebhookDeliveries(storage storage.Storage) *WebhookDeliveries {
+	return &WebhookDeliveries{storage: storage}
+}
+
+// ServeHTTP Returns the most recently attempted outgoing webhook
+// deliveries for an app, newest first. Accepts an optional "limit" query
+// parameter (default 20).
+//
+// GET /apps/{app_id}/webhooks/deliveries
+func (h *WebhookDeliveries) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		pathVars = mux.Vars(r)
+		appID    = pathVars["app_id"]
+	)
+
+	app, err := h.storage.GetAppByAppID(appID)
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Could not found an app with app_id: %s", appID), http.StatusBadRequest)
+		return
+	}
+
+	limit := 20
+
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil {
+			limit = parsed
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	js := map[string]interface{}{"deliveries": app.RecentWebhookDeliveries(limit)}
+
+	if err := json.NewEncoder(w).Encode(js); err != nil {
+		log.Error(err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
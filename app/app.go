@@ -0,0 +1,265 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"nuntius/channel"
+	"nuntius/connection"
+	"nuntius/events"
+	"nuntius/subscription"
+	"nuntius/webhooks"
+)
+
+// App is a single configured Pusher-compatible application: its
+// credentials and the channels currently in use on it.
+type App struct {
+	ID      string
+	Key     string
+	Secret  string
+	Enabled bool
+
+	// Webhooks are the operator-configured endpoints, loaded from the
+	// app's YAML config, that are notified of channel lifecycle and
+	// client events.
+	Webhooks []webhooks.WebhookConfig
+
+	// PresenceGroupingAttribute, when set, is the user_info field
+	// SearchChannelUsers splits presence search results on (e.g. "team").
+	PresenceGroupingAttribute string
+
+	// ReadTimeout, WriteTimeout and IdleTimeout bound how long a
+	// connection may go without a read or write before it is evicted.
+	// PingInterval, if set, is how often a liveness ping is sent to keep
+	// an otherwise-idle connection from tripping IdleTimeout.
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	PingInterval time.Duration `yaml:"ping_interval"`
+
+	// QueueSize and QueuePolicy override the bounded outbound queue size
+	// and backpressure policy connection.New gives a connection by
+	// default, so a slow-consumer policy can be tuned per app. Leaving
+	// QueueSize at zero keeps ConfigureConnection from touching the
+	// connection.New defaults.
+	QueueSize   int                           `yaml:"queue_size"`
+	QueuePolicy connection.BackpressurePolicy `yaml:"queue_policy"`
+
+	mu       sync.RWMutex
+	channels map[string]*channel.Channel
+
+	eventsIn   uint64
+	dispatcher *webhooks.Dispatcher
+}
+
+// New creates an empty, enabled App.
+func New(id, key, secret string) *App {
+	return &App{
+		ID:       id,
+		Key:      key,
+		Secret:   secret,
+		Enabled:  true,
+		channels: make(map[string]*channel.Channel),
+	}
+}
+
+// SetWebhookDispatcher wires the dispatcher used to deliver Webhooks.
+// Typically called once at startup, after Webhooks has been loaded from
+// config.
+func (a *App) SetWebhookDispatcher(d *webhooks.Dispatcher) {
+	a.dispatcher = d
+}
+
+// FindOrCreateChannelByChannelID returns the channel with id, creating it
+// if it does not yet exist.
+func (a *App) FindOrCreateChannelByChannelID(id string) *channel.Channel {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if c, ok := a.channels[id]; ok {
+		return c
+	}
+
+	c := channel.New(id)
+	a.channels[id] = c
+
+	return c
+}
+
+// FindChannelByChannelID returns the channel with id, or an error if it
+// does not exist.
+func (a *App) FindChannelByChannelID(id string) (*channel.Channel, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	c, ok := a.channels[id]
+
+	if !ok {
+		return nil, fmt.Errorf("channel %s not found", id)
+	}
+
+	return c, nil
+}
+
+// Channels returns every channel currently in use on the app.
+func (a *App) Channels() []*channel.Channel {
+	return a.filterChannels(func(*channel.Channel) bool { return true })
+}
+
+// PublicChannels returns every public channel currently in use on the app.
+func (a *App) PublicChannels() []*channel.Channel {
+	return a.filterChannels(func(c *channel.Channel) bool { return c.IsPublic() })
+}
+
+// PrivateChannels returns every private channel currently in use on the app.
+func (a *App) PrivateChannels() []*channel.Channel {
+	return a.filterChannels(func(c *channel.Channel) bool { return c.IsPrivate() })
+}
+
+// PresenceChannels returns every presence channel currently in use on the app.
+func (a *App) PresenceChannels() []*channel.Channel {
+	return a.filterChannels(func(c *channel.Channel) bool { return c.IsPresence() })
+}
+
+func (a *App) filterChannels(match func(*channel.Channel) bool) []*channel.Channel {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var channels []*channel.Channel
+
+	for _, c := range a.channels {
+		if match(c) {
+			channels = append(channels, c)
+		}
+	}
+
+	return channels
+}
+
+// ConfigureConnection applies the app's ReadTimeout/WriteTimeout/
+// IdleTimeout/PingInterval/QueueSize/QueuePolicy defaults to a newly
+// accepted connection. Callers should run it once, right after
+// connection.New.
+func (a *App) ConfigureConnection(c *connection.Connection) {
+	if a.ReadTimeout > 0 {
+		c.SetReadDeadline(time.Now().Add(a.ReadTimeout))
+	}
+
+	if a.WriteTimeout > 0 {
+		c.SetWriteDeadline(time.Now().Add(a.WriteTimeout))
+	}
+
+	if a.IdleTimeout > 0 {
+		c.SetIdleTimeout(a.IdleTimeout)
+	}
+
+	if a.QueueSize > 0 {
+		c.SetQueue(a.QueueSize, a.QueuePolicy)
+	}
+
+	c.StartPing(a.PingInterval)
+}
+
+// Subscribe adds s to c, firing a channel_occupied webhook if c was
+// previously empty and a member_added webhook if c is a presence channel.
+// It also arms s.Connection.OnEvict to unsubscribe s from c, so a
+// connection evicted for going idle or stalling its read loop doesn't
+// hold its presence slot or channel membership indefinitely.
+func (a *App) Subscribe(c *channel.Channel, s *subscription.Subscription) {
+	wasOccupied := c.IsOccupied()
+
+	c.Subscribe(s)
+
+	previousOnEvict := s.Connection.OnEvict
+	s.Connection.OnEvict = func() {
+		if previousOnEvict != nil {
+			previousOnEvict()
+		}
+
+		a.Unsubscribe(c, s.ID)
+	}
+
+	if !wasOccupied {
+		a.fireWebhook(webhooks.Event{Name: webhooks.EventChannelOccupied, Channel: c.ID})
+	}
+
+	if c.IsPresence() {
+		a.fireWebhook(webhooks.Event{Name: webhooks.EventMemberAdded, Channel: c.ID, UserID: s.ID})
+	}
+}
+
+// Unsubscribe removes the subscription identified by id from c, firing a
+// member_removed webhook if c is a presence channel and a
+// channel_vacated webhook if c is now empty.
+func (a *App) Unsubscribe(c *channel.Channel, id string) {
+	c.Unsubscribe(id)
+
+	if c.IsPresence() {
+		a.fireWebhook(webhooks.Event{Name: webhooks.EventMemberRemoved, Channel: c.ID, UserID: id})
+	}
+
+	if !c.IsOccupied() {
+		a.fireWebhook(webhooks.Event{Name: webhooks.EventChannelVacated, Channel: c.ID})
+	}
+}
+
+// Publish fans a raw event out to every subscriber of its channel,
+// excluding the connection identified by excludeSocketID if any, and
+// fires a client_event webhook.
+func (a *App) Publish(c *channel.Channel, event events.Raw, excludeSocketID string) error {
+	atomic.AddUint64(&a.eventsIn, 1)
+
+	if err := c.Publish(event, excludeSocketID); err != nil {
+		return err
+	}
+
+	a.fireWebhook(webhooks.Event{
+		Name:    webhooks.EventClientEvent,
+		Channel: c.ID,
+		Event:   event.Event,
+		Data:    event.Data,
+	})
+
+	return nil
+}
+
+// fireWebhook delivers event to every configured webhook, if any, via the
+// app's dispatcher.
+func (a *App) fireWebhook(event webhooks.Event) {
+	if a.dispatcher == nil || len(a.Webhooks) == 0 {
+		return
+	}
+
+	event.Time = time.Now()
+
+	a.dispatcher.Deliver(a.Webhooks, event)
+}
+
+// RecentWebhookDeliveries returns up to limit of the most recently
+// attempted webhook deliveries, newest first.
+func (a *App) RecentWebhookDeliveries(limit int) []webhooks.Delivery {
+	if a.dispatcher == nil {
+		return []webhooks.Delivery{}
+	}
+
+	return a.dispatcher.RecentDeliveries(limit)
+}
+
+// Stats returns aggregate publish/delivery and ping/pong liveness
+// counters for the app, so operators can spot slow or half-open
+// connections before they start blocking publishes.
+func (a *App) Stats() (eventsIn, eventsOut, drops, pingsOut, pongsIn uint64) {
+	eventsIn = atomic.LoadUint64(&a.eventsIn)
+
+	for _, c := range a.Channels() {
+		out, d, pOut, pIn := c.Stats()
+		eventsOut += out
+		drops += d
+		pingsOut += pOut
+		pongsIn += pIn
+	}
+
+	return
+}
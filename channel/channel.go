@@ -0,0 +1,135 @@
+package channel
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"nuntius/events"
+	"nuntius/subscription"
+	"nuntius/utils"
+)
+
+// Channel is a single Pusher-compatible channel and its current subscribers.
+type Channel struct {
+	ID string
+
+	mu            sync.RWMutex
+	subscriptions map[string]*subscription.Subscription
+	prefixIndex   map[string][]string
+}
+
+// New creates an empty, unoccupied Channel.
+func New(id string) *Channel {
+	return &Channel{
+		ID:            id,
+		subscriptions: make(map[string]*subscription.Subscription),
+	}
+}
+
+// IsPresence reports whether the channel is a presence channel.
+func (c *Channel) IsPresence() bool { return utils.IsPresenceChannel(c.ID) }
+
+// IsPrivate reports whether the channel is a private channel.
+func (c *Channel) IsPrivate() bool { return utils.IsPrivateChannel(c.ID) }
+
+// IsPublic reports whether the channel is neither private nor presence.
+func (c *Channel) IsPublic() bool { return !c.IsPrivate() && !c.IsPresence() }
+
+// IsOccupied reports whether the channel currently has any subscribers.
+func (c *Channel) IsOccupied() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.subscriptions) > 0
+}
+
+// TotalSubscriptions returns the number of active subscriptions.
+func (c *Channel) TotalSubscriptions() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.subscriptions)
+}
+
+// TotalUsers returns the number of distinct users subscribed, which for
+// non-presence channels is the same as TotalSubscriptions.
+func (c *Channel) TotalUsers() int {
+	return c.TotalSubscriptions()
+}
+
+// Subscribe adds s as a subscriber of the channel.
+func (c *Channel) Subscribe(s *subscription.Subscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subscriptions[s.ID] = s
+	c.prefixIndex = nil
+}
+
+// Unsubscribe removes the subscription identified by id.
+func (c *Channel) Unsubscribe(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.subscriptions, id)
+	c.prefixIndex = nil
+}
+
+// Subscriptions returns every current subscriber, stably ordered by
+// subscribe time then ID so callers can page through them with a cursor.
+func (c *Channel) Subscriptions() []*subscription.Subscription {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	subs := make([]*subscription.Subscription, 0, len(c.subscriptions))
+	for _, s := range c.subscriptions {
+		subs = append(subs, s)
+	}
+
+	sort.Slice(subs, func(i, j int) bool {
+		if !subs[i].SubscribedAt.Equal(subs[j].SubscribedAt) {
+			return subs[i].SubscribedAt.Before(subs[j].SubscribedAt)
+		}
+
+		return subs[i].ID < subs[j].ID
+	})
+
+	return subs
+}
+
+// Publish fans event out to every subscriber, optionally excluding the
+// connection that published it.
+func (c *Channel) Publish(event events.Raw, excludeSocketID string) error {
+	payload, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	for _, s := range c.Subscriptions() {
+		if excludeSocketID != "" && s.Connection.SocketID == excludeSocketID {
+			continue
+		}
+
+		s.Send(payload)
+	}
+
+	return nil
+}
+
+// Stats returns aggregate delivery and ping/pong liveness counters across
+// every subscriber's connection on the channel.
+func (c *Channel) Stats() (eventsOut, drops, pingsOut, pongsIn uint64) {
+	for _, s := range c.Subscriptions() {
+		out, d := s.Connection.Stats()
+		eventsOut += out
+		drops += d
+
+		pOut, pIn := s.Connection.PingStats()
+		pingsOut += pOut
+		pongsIn += pIn
+	}
+
+	return
+}
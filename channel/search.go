@@ -0,0 +1,160 @@
+package channel
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"nuntius/subscription"
+)
+
+// PresenceUser is a single match returned by SearchUsers: a subscriber's
+// ID plus the user_info they subscribed with.
+type PresenceUser struct {
+	ID   string                 `json:"id"`
+	Info map[string]interface{} `json:"user_info,omitempty"`
+}
+
+// Page returns up to limit subscribers starting immediately after cursor
+// (the ID of the last subscriber on the previous page), in Subscriptions
+// order.
+func (c *Channel) Page(cursor string, limit int) (subs []*subscription.Subscription, nextCursor string) {
+	all := c.Subscriptions()
+
+	start := indexAfterCursor(all, cursor)
+	end := pageEnd(start, limit, len(all))
+
+	subs = all[start:end]
+
+	if end < len(all) {
+		nextCursor = subs[len(subs)-1].ID
+	}
+
+	return subs, nextCursor
+}
+
+// SearchUsers returns presence-channel subscribers whose ID or any string
+// field of their user_info matches a case-insensitive prefix of query,
+// paginated via cursor/limit.
+//
+// When groupingAttribute and group are both non-empty, matches are split
+// into inChannel (their user_info[groupingAttribute] equals group) and
+// outOfChannel (it doesn't). Without groupingAttribute every match is
+// reported as inChannel; outOfChannel can never include users who have
+// never subscribed to the channel, since nuntius has no external user
+// directory to draw them from.
+func (c *Channel) SearchUsers(query, cursor string, limit int, groupingAttribute, group string) (inChannel, outOfChannel []PresenceUser, nextCursor string) {
+	index := c.buildPrefixIndex()
+	query = strings.ToLower(query)
+
+	var matched []*subscription.Subscription
+
+	for _, s := range c.Subscriptions() {
+		if query == "" || matchesPrefix(index[s.ID], query) {
+			matched = append(matched, s)
+		}
+	}
+
+	start := indexAfterCursor(matched, cursor)
+	end := pageEnd(start, limit, len(matched))
+
+	for _, s := range matched[start:end] {
+		user := PresenceUser{ID: s.ID, Info: decodeUserInfo(s.Data)}
+
+		if groupingAttribute != "" && group != "" && fmt.Sprintf("%v", user.Info[groupingAttribute]) != group {
+			outOfChannel = append(outOfChannel, user)
+		} else {
+			inChannel = append(inChannel, user)
+		}
+	}
+
+	if end < len(matched) {
+		nextCursor = matched[end-1].ID
+	}
+
+	return inChannel, outOfChannel, nextCursor
+}
+
+// buildPrefixIndex lazily builds a case-insensitive index of every string
+// worth prefix-matching against for each current subscriber: its ID and
+// every string field inside its user_info payload. It is invalidated by
+// Subscribe/Unsubscribe.
+func (c *Channel) buildPrefixIndex() map[string][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.prefixIndex != nil {
+		return c.prefixIndex
+	}
+
+	index := make(map[string][]string, len(c.subscriptions))
+
+	for id, s := range c.subscriptions {
+		values := []string{strings.ToLower(id)}
+
+		for _, v := range decodeUserInfo(s.Data) {
+			if str, ok := v.(string); ok {
+				values = append(values, strings.ToLower(str))
+			}
+		}
+
+		index[id] = values
+	}
+
+	c.prefixIndex = index
+
+	return index
+}
+
+func matchesPrefix(values []string, query string) bool {
+	for _, v := range values {
+		if strings.HasPrefix(v, query) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func decodeUserInfo(data string) map[string]interface{} {
+	var payload struct {
+		UserInfo map[string]interface{} `json:"user_info"`
+	}
+
+	if err := json.Unmarshal([]byte(data), &payload); err != nil {
+		return nil
+	}
+
+	return payload.UserInfo
+}
+
+func indexAfterCursor(subs []*subscription.Subscription, cursor string) int {
+	if cursor == "" {
+		return 0
+	}
+
+	for i, s := range subs {
+		if s.ID == cursor {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+func pageEnd(start, limit, total int) int {
+	if start > total {
+		start = total
+	}
+
+	if limit <= 0 {
+		return total
+	}
+
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return end
+}
@@ -0,0 +1,133 @@
+package channel
+
+import (
+	"fmt"
+	"testing"
+
+	"nuntius/subscription"
+)
+
+func subscribeUser(c *Channel, id, userInfoJSON string) {
+	s := subscription.New(nil, fmt.Sprintf(`{"user_info":%s}`, userInfoJSON))
+	s.ID = id
+
+	c.Subscribe(s)
+}
+
+func TestChannelPage(t *testing.T) {
+	c := New("presence-room")
+
+	for i := 0; i < 5; i++ {
+		subscribeUser(c, fmt.Sprintf("user-%d", i), `{}`)
+	}
+
+	first, cursor := c.Page("", 2)
+
+	if len(first) != 2 {
+		t.Fatalf("len(first) == %d, wants %d", len(first), 2)
+	}
+
+	if cursor == "" {
+		t.Fatalf("expected a non-empty next_cursor when more pages remain")
+	}
+
+	second, cursor := c.Page(cursor, 2)
+
+	if len(second) != 2 {
+		t.Fatalf("len(second) == %d, wants %d", len(second), 2)
+	}
+
+	third, cursor := c.Page(cursor, 2)
+
+	if len(third) != 1 {
+		t.Fatalf("len(third) == %d, wants %d", len(third), 1)
+	}
+
+	if cursor != "" {
+		t.Errorf("next_cursor == %q, wants empty once every subscriber has been paged", cursor)
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range append(append(first, second...), third...) {
+		if seen[s.ID] {
+			t.Errorf("subscriber %s returned more than once across pages", s.ID)
+		}
+		seen[s.ID] = true
+	}
+
+	if len(seen) != 5 {
+		t.Errorf("paged through %d distinct subscribers, wants %d", len(seen), 5)
+	}
+}
+
+func TestChannelSearchUsersMatchesIDAndUserInfoPrefix(t *testing.T) {
+	c := New("presence-room")
+
+	subscribeUser(c, "alice", `{"name": "Alice Smith"}`)
+	subscribeUser(c, "bob", `{"name": "Bob Jones"}`)
+
+	inChannel, _, _ := c.SearchUsers("ali", "", 20, "", "")
+
+	if len(inChannel) != 1 || inChannel[0].ID != "alice" {
+		t.Fatalf("SearchUsers(\"ali\", ...) == %+v, wants a single match on alice", inChannel)
+	}
+
+	// Matches on a user_info field too, case-insensitively.
+	inChannel, _, _ = c.SearchUsers("BOB J", "", 20, "", "")
+
+	if len(inChannel) != 1 || inChannel[0].ID != "bob" {
+		t.Fatalf("SearchUsers(\"BOB J\", ...) == %+v, wants a single match on bob", inChannel)
+	}
+
+	// No prefix match.
+	inChannel, _, _ = c.SearchUsers("zzz", "", 20, "", "")
+
+	if len(inChannel) != 0 {
+		t.Errorf("SearchUsers(\"zzz\", ...) == %+v, wants no matches", inChannel)
+	}
+}
+
+func TestChannelSearchUsersSplitsByGroupingAttribute(t *testing.T) {
+	c := New("presence-room")
+
+	subscribeUser(c, "alice", `{"team": "red"}`)
+	subscribeUser(c, "bob", `{"team": "blue"}`)
+
+	inChannel, outOfChannel, _ := c.SearchUsers("", "", 20, "team", "red")
+
+	if len(inChannel) != 1 || inChannel[0].ID != "alice" {
+		t.Fatalf("inChannel == %+v, wants only alice", inChannel)
+	}
+
+	if len(outOfChannel) != 1 || outOfChannel[0].ID != "bob" {
+		t.Fatalf("outOfChannel == %+v, wants only bob", outOfChannel)
+	}
+}
+
+func TestChannelSearchUsersPaginates(t *testing.T) {
+	c := New("presence-room")
+
+	for i := 0; i < 5; i++ {
+		subscribeUser(c, fmt.Sprintf("match-%d", i), `{}`)
+	}
+
+	inChannel, _, cursor := c.SearchUsers("match", "", 2, "", "")
+
+	if len(inChannel) != 2 {
+		t.Fatalf("len(inChannel) == %d, wants %d", len(inChannel), 2)
+	}
+
+	if cursor == "" {
+		t.Fatalf("expected a non-empty next_cursor when more matches remain")
+	}
+
+	rest, _, cursor := c.SearchUsers("match", cursor, 20, "", "")
+
+	if len(rest) != 3 {
+		t.Fatalf("len(rest) == %d, wants %d", len(rest), 3)
+	}
+
+	if cursor != "" {
+		t.Errorf("next_cursor == %q, wants empty once every match has been paged", cursor)
+	}
+}
@@ -0,0 +1,347 @@
+package connection
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Ping/pong WebSocket control frame types, matching gorilla/websocket's
+// message type constants.
+const (
+	PingMessage = 9
+	PongMessage = 10
+)
+
+// Socket is the transport a Connection reads frames from and writes
+// frames to. It is satisfied by a *websocket.Conn.
+type Socket interface {
+	ReadMessage() (messageType int, data []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	SetReadDeadline(deadline time.Time) error
+	SetWriteDeadline(deadline time.Time) error
+
+	// SetPingHandler and SetPongHandler register callbacks for control
+	// frames gorilla/websocket's ReadMessage consumes internally and
+	// never returns to the caller, so this is the only place a
+	// Connection can observe them.
+	SetPingHandler(h func(appData string) error)
+	SetPongHandler(h func(appData string) error)
+
+	Close() error
+}
+
+// BackpressurePolicy controls what happens when a Connection's outbound
+// queue is full and a slow consumer can't keep up with publishes.
+type BackpressurePolicy int
+
+const (
+	// DropPolicy discards the newest message and counts it as a drop.
+	DropPolicy BackpressurePolicy = iota
+	// KickPolicy closes the connection, evicting the slow consumer.
+	KickPolicy
+)
+
+// defaultQueueSize is the number of pending outbound messages a
+// Connection buffers before applying its BackpressurePolicy.
+const defaultQueueSize = 256
+
+// Connection represents a single WebSocket client connection.
+type Connection struct {
+	SocketID  string
+	Socket    Socket
+	CreatedAt time.Time
+
+	// OnEvict, if set, is called after the connection is closed because
+	// its idle timeout fired or its read loop errored, so the owner can
+	// fire member_removed/channel_vacated webhooks.
+	OnEvict func()
+
+	*deadlineTimer
+	idleTimeout time.Duration
+
+	// writeMu serializes every WriteMessage call, since gorilla/websocket
+	// forbids concurrent writers and both pump and StartPing's ticker
+	// goroutine write to the same socket.
+	writeMu sync.Mutex
+
+	evictOnce sync.Once
+
+	// queueMu guards policy, outbox and stopPump, since SetQueue can be
+	// called again (to apply a per-app queue size/policy) while Send and
+	// the previous pump goroutine are still using the fields it swaps.
+	queueMu  sync.Mutex
+	policy   BackpressurePolicy
+	outbox   chan []byte
+	stopPump chan struct{}
+
+	eventsOut uint64
+	drops     uint64
+	pingsOut  uint64
+	pongsIn   uint64
+}
+
+// New creates a new Connection wrapping socket, with a default bounded
+// outbound queue and drop policy for slow consumers.
+func New(socketID string, socket Socket) *Connection {
+	c := &Connection{
+		SocketID:      socketID,
+		Socket:        socket,
+		CreatedAt:     time.Now(),
+		deadlineTimer: newDeadlineTimer(),
+	}
+
+	// gorilla/websocket's ReadMessage swallows Ping/Pong control frames
+	// internally and never returns them, so pongsIn and idle liveness
+	// have to be driven from these handlers rather than ReadLoop.
+	c.Socket.SetPongHandler(func(string) error {
+		atomic.AddUint64(&c.pongsIn, 1)
+		c.Touch()
+		return nil
+	})
+
+	c.Socket.SetPingHandler(func(appData string) error {
+		c.Touch()
+
+		c.writeMu.Lock()
+		defer c.writeMu.Unlock()
+
+		return c.Socket.WriteMessage(PongMessage, []byte(appData))
+	})
+
+	c.SetQueue(defaultQueueSize, DropPolicy)
+
+	return c
+}
+
+// SetQueue (re)configures the size and backpressure policy of the
+// outbound queue, so callers can override the defaults used by New on a
+// per-app basis. The previous pump goroutine, if any, is stopped rather
+// than left ranging over an orphaned channel.
+func (c *Connection) SetQueue(size int, policy BackpressurePolicy) {
+	c.queueMu.Lock()
+
+	if c.stopPump != nil {
+		close(c.stopPump)
+	}
+
+	c.policy = policy
+	c.outbox = make(chan []byte, size)
+	stop := make(chan struct{})
+	c.stopPump = stop
+	outbox := c.outbox
+
+	c.queueMu.Unlock()
+
+	go c.pump(outbox, stop)
+}
+
+// SetReadDeadline arms the read-side deadline on both the underlying
+// socket and this Connection's cancel channel. A zero deadline disables
+// the read timeout.
+func (c *Connection) SetReadDeadline(deadline time.Time) {
+	c.Socket.SetReadDeadline(deadline)
+	c.armRead(deadline)
+}
+
+// SetWriteDeadline arms the write-side deadline on both the underlying
+// socket and this Connection's cancel channel, so a publish can't block
+// on a slow consumer forever. A zero deadline disables the write timeout.
+func (c *Connection) SetWriteDeadline(deadline time.Time) {
+	c.Socket.SetWriteDeadline(deadline)
+	c.armWrite(deadline)
+}
+
+// SetIdleTimeout arms both the read and write deadlines d from now, and
+// re-arms them by the same amount every time Touch is called. Pass zero
+// to disable idle eviction.
+func (c *Connection) SetIdleTimeout(d time.Duration) {
+	c.idleTimeout = d
+	c.Touch()
+}
+
+// Touch re-arms the idle deadline from now, if SetIdleTimeout has been
+// called. It should be invoked whenever a frame is read from or written
+// to the connection.
+func (c *Connection) Touch() {
+	if c.idleTimeout <= 0 {
+		return
+	}
+
+	deadline := time.Now().Add(c.idleTimeout)
+	c.SetReadDeadline(deadline)
+	c.SetWriteDeadline(deadline)
+}
+
+// Send enqueues data for delivery to the underlying socket. If the
+// outbound queue is full, the configured BackpressurePolicy is applied
+// instead of blocking the publisher.
+func (c *Connection) Send(data []byte) {
+	c.queueMu.Lock()
+	outbox, policy := c.outbox, c.policy
+	c.queueMu.Unlock()
+
+	select {
+	case outbox <- data:
+	default:
+		switch policy {
+		case KickPolicy:
+			c.evict()
+		default:
+			atomic.AddUint64(&c.drops, 1)
+		}
+	}
+}
+
+// pump drains outbox onto the socket until a later SetQueue call closes
+// stop to retire it, or a write fails. Each write races the connection's
+// write deadline: the Socket's own SetWriteDeadline is what actually
+// unblocks a stalled WriteMessage, but evicting as soon as writeCancelChan
+// closes means a publisher isn't left waiting on a Socket implementation
+// that doesn't honor its deadline.
+func (c *Connection) pump(outbox chan []byte, stop chan struct{}) {
+	for {
+		select {
+		case data := <-outbox:
+			if !c.write(1, data) {
+				return
+			}
+
+			atomic.AddUint64(&c.eventsOut, 1)
+			c.Touch()
+
+		case <-stop:
+			return
+		}
+	}
+}
+
+// write sends messageType/data to the socket, reporting false (and
+// evicting the connection) if the write fails or the write deadline
+// fires first.
+func (c *Connection) write(messageType int, data []byte) bool {
+	done := make(chan error, 1)
+
+	go func() {
+		c.writeMu.Lock()
+		defer c.writeMu.Unlock()
+
+		done <- c.Socket.WriteMessage(messageType, data)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			c.evict()
+			return false
+		}
+
+		return true
+
+	case <-c.writeCancelChan():
+		c.evict()
+		return false
+	}
+}
+
+// StartPing sends a ping control frame every interval until a write
+// fails, so an otherwise-idle-but-alive connection keeps its idle
+// deadline from firing. Run it in its own goroutine per connection.
+func (c *Connection) StartPing(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if !c.write(PingMessage, nil) {
+				return
+			}
+
+			atomic.AddUint64(&c.pingsOut, 1)
+		}
+	}()
+}
+
+// readResult is one frame (or error) read off a Connection's socket.
+type readResult struct {
+	messageType int
+	data        []byte
+	err         error
+}
+
+// ReadLoop reads frames from the connection's socket, handing each one
+// to onMessage, until the socket errors or the read deadline fires. In
+// either case the connection is evicted. Ping/Pong control frames never
+// reach here: gorilla/websocket's ReadMessage consumes them internally
+// and invokes the handlers registered in New instead.
+//
+// Each read races the connection's read deadline: the Socket's own
+// SetReadDeadline is what actually unblocks a stalled ReadMessage, but
+// evicting as soon as readCancelChan closes means a half-open connection
+// isn't left holding its presence slot on a Socket implementation that
+// doesn't honor its deadline. Run it in its own goroutine per connection.
+func (c *Connection) ReadLoop(onMessage func(messageType int, data []byte)) {
+	results := make(chan readResult, 1)
+
+	go func() {
+		for {
+			messageType, data, err := c.Socket.ReadMessage()
+			results <- readResult{messageType, data, err}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case res := <-results:
+			if res.err != nil {
+				c.evict()
+				return
+			}
+
+			c.Touch()
+
+			if onMessage != nil {
+				onMessage(res.messageType, res.data)
+			}
+
+		case <-c.readCancelChan():
+			c.evict()
+			return
+		}
+	}
+}
+
+// evict closes the underlying socket and notifies OnEvict, if set. It is
+// safe to call more than once (the read loop, the pump and StartPing can
+// all observe the same dead socket) but only acts on the first call, so
+// OnEvict fires exactly once per connection.
+func (c *Connection) evict() {
+	c.evictOnce.Do(func() {
+		c.Socket.Close()
+
+		if c.OnEvict != nil {
+			c.OnEvict()
+		}
+	})
+}
+
+// Stats returns the number of messages delivered and dropped on this
+// connection's outbound queue.
+func (c *Connection) Stats() (eventsOut, drops uint64) {
+	return atomic.LoadUint64(&c.eventsOut), atomic.LoadUint64(&c.drops)
+}
+
+// PingStats returns the number of ping frames sent and pong frames
+// received on this connection.
+func (c *Connection) PingStats() (pingsOut, pongsIn uint64) {
+	return atomic.LoadUint64(&c.pingsOut), atomic.LoadUint64(&c.pongsIn)
+}
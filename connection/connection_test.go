@@ -3,8 +3,56 @@ package connection
 import (
 	"nuntius/mocks"
 	"testing"
+	"time"
 )
 
+// blockingSocket signals onWrite as soon as WriteMessage is entered, then
+// blocks until released. Tests use onWrite to wait until the pump has
+// dequeued a message before asserting on the state of the outbound queue.
+type blockingSocket struct {
+	onWrite chan struct{}
+	release chan struct{}
+	closed  chan struct{}
+
+	// pongHandler/pingHandler capture the callback New registers via
+	// SetPongHandler/SetPingHandler, so tests can invoke it directly the
+	// way gorilla/websocket would when a control frame arrives.
+	pongHandler chan func(appData string) error
+	pingHandler chan func(appData string) error
+}
+
+func newBlockingSocket() blockingSocket {
+	return blockingSocket{
+		onWrite:     make(chan struct{}, 1),
+		release:     make(chan struct{}),
+		closed:      make(chan struct{}, 1),
+		pongHandler: make(chan func(appData string) error, 1),
+		pingHandler: make(chan func(appData string) error, 1),
+	}
+}
+
+func (s blockingSocket) ReadMessage() (int, []byte, error) {
+	<-s.release
+	return 0, nil, nil
+}
+
+func (s blockingSocket) WriteMessage(messageType int, data []byte) error {
+	s.onWrite <- struct{}{}
+	<-s.release
+	return nil
+}
+
+func (s blockingSocket) SetReadDeadline(deadline time.Time) error  { return nil }
+func (s blockingSocket) SetWriteDeadline(deadline time.Time) error { return nil }
+
+func (s blockingSocket) SetPongHandler(h func(appData string) error) { s.pongHandler <- h }
+func (s blockingSocket) SetPingHandler(h func(appData string) error) { s.pingHandler <- h }
+
+func (s blockingSocket) Close() error {
+	s.closed <- struct{}{}
+	return nil
+}
+
 func TestNewConnection(t *testing.T) {
 	expectedSocketID := "socketID"
 	expectedSocket := mocks.MockSocket{}
@@ -23,3 +71,130 @@ func TestNewConnection(t *testing.T) {
 		t.Errorf("c.createdAt.IsZero() == %t, wants %t", c.CreatedAt.IsZero(), false)
 	}
 }
+
+func TestConnectionSendDropsWhenQueueFull(t *testing.T) {
+	socket := newBlockingSocket()
+	defer close(socket.release)
+
+	c := New("socketID", socket)
+	c.SetQueue(1, DropPolicy)
+
+	// The pump dequeues "a" and blocks inside WriteMessage, so the queue
+	// is empty again by the time we wait on onWrite.
+	c.Send([]byte("a"))
+	<-socket.onWrite
+
+	// "b" fills the now-empty queue; "c" has nowhere to go and is dropped.
+	c.Send([]byte("b"))
+	c.Send([]byte("c"))
+
+	if _, drops := c.Stats(); drops != 1 {
+		t.Errorf("drops == %d, wants %d", drops, 1)
+	}
+}
+
+func TestConnectionSendKicksSlowConsumer(t *testing.T) {
+	socket := newBlockingSocket()
+	defer close(socket.release)
+
+	c := New("socketID", socket)
+	c.SetQueue(1, KickPolicy)
+
+	c.Send([]byte("a"))
+	<-socket.onWrite
+
+	c.Send([]byte("b"))
+	c.Send([]byte("c"))
+
+	select {
+	case <-socket.closed:
+	default:
+		t.Errorf("expected slow consumer connection to be closed")
+	}
+}
+
+func TestSetIdleTimeoutArmsReadAndWriteCancel(t *testing.T) {
+	socket := newBlockingSocket()
+	defer close(socket.release)
+
+	c := New("socketID", socket)
+	c.SetIdleTimeout(10 * time.Millisecond)
+
+	select {
+	case <-c.readCancelChan():
+	case <-time.After(200 * time.Millisecond):
+		t.Errorf("expected read deadline to fire within the idle timeout")
+	}
+
+	select {
+	case <-c.writeCancelChan():
+	case <-time.After(200 * time.Millisecond):
+		t.Errorf("expected write deadline to fire within the idle timeout")
+	}
+}
+
+func TestPongHandlerCountsAndTouches(t *testing.T) {
+	socket := newBlockingSocket()
+	defer close(socket.release)
+
+	c := New("socketID", socket)
+	c.SetIdleTimeout(10 * time.Millisecond)
+
+	pong := <-socket.pongHandler
+	before := c.readCancelChan()
+
+	if err := pong(""); err != nil {
+		t.Fatalf("pongHandler(\"\") == %v, wants nil", err)
+	}
+
+	if _, pongsIn := c.PingStats(); pongsIn != 1 {
+		t.Errorf("pongsIn == %d, wants %d", pongsIn, 1)
+	}
+
+	select {
+	case <-before:
+	default:
+		t.Errorf("expected a pong frame to re-arm the idle read deadline")
+	}
+}
+
+func TestReadLoopEvictsWhenReadDeadlineFires(t *testing.T) {
+	socket := newBlockingSocket()
+	defer close(socket.release)
+
+	c := New("socketID", socket)
+
+	// blockingSocket.SetReadDeadline is a no-op, so this only evicts if
+	// ReadLoop itself races readCancelChan rather than trusting the
+	// Socket to unblock ReadMessage.
+	c.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+
+	go c.ReadLoop(nil)
+
+	select {
+	case <-socket.closed:
+	case <-time.After(200 * time.Millisecond):
+		t.Errorf("expected ReadLoop to evict the connection once its read deadline fired")
+	}
+}
+
+func TestPumpEvictsWhenWriteDeadlineFires(t *testing.T) {
+	socket := newBlockingSocket()
+	defer close(socket.release)
+
+	c := New("socketID", socket)
+	c.SetQueue(1, DropPolicy)
+
+	// blockingSocket.SetWriteDeadline is a no-op, so this only evicts if
+	// pump itself races writeCancelChan rather than trusting the Socket
+	// to unblock WriteMessage.
+	c.SetWriteDeadline(time.Now().Add(10 * time.Millisecond))
+
+	c.Send([]byte("a"))
+
+	select {
+	case <-socket.closed:
+	case <-time.After(200 * time.Millisecond):
+		t.Errorf("expected pump to evict the connection once its write deadline fired")
+	}
+}
@@ -0,0 +1,89 @@
+package connection
+
+import (
+	"sync"
+	"time"
+)
+
+// deadlineTimer mirrors the deadline machinery used by gonet-style
+// network adapters: each direction owns a cancel channel that closes when
+// its deadline fires, so anything selecting on it unblocks instead of
+// waiting on the underlying socket forever. Setting a new deadline
+// retires the previous cancel channel before arming the next timer.
+type deadlineTimer struct {
+	mu sync.Mutex
+
+	readTimer  *time.Timer
+	readCancel chan struct{}
+
+	writeTimer  *time.Timer
+	writeCancel chan struct{}
+}
+
+func newDeadlineTimer() *deadlineTimer {
+	return &deadlineTimer{
+		readCancel:  make(chan struct{}),
+		writeCancel: make(chan struct{}),
+	}
+}
+
+// armRead closes the previous read cancel channel and, unless deadline is
+// zero, arms a timer that closes the new one when deadline passes.
+func (d *deadlineTimer) armRead(deadline time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.readTimer != nil {
+		d.readTimer.Stop()
+	}
+
+	close(d.readCancel)
+	d.readCancel = make(chan struct{})
+
+	if deadline.IsZero() {
+		d.readTimer = nil
+		return
+	}
+
+	cancel := d.readCancel
+	d.readTimer = time.AfterFunc(time.Until(deadline), func() { close(cancel) })
+}
+
+// armWrite is armRead's write-side counterpart.
+func (d *deadlineTimer) armWrite(deadline time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.writeTimer != nil {
+		d.writeTimer.Stop()
+	}
+
+	close(d.writeCancel)
+	d.writeCancel = make(chan struct{})
+
+	if deadline.IsZero() {
+		d.writeTimer = nil
+		return
+	}
+
+	cancel := d.writeCancel
+	d.writeTimer = time.AfterFunc(time.Until(deadline), func() { close(cancel) })
+}
+
+// readCancelChan returns the channel that closes when the current read
+// deadline fires.
+func (d *deadlineTimer) readCancelChan() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.readCancel
+}
+
+// writeCancelChan returns the channel that closes when the current write
+// deadline fires.
+func (d *deadlineTimer) writeCancelChan() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.writeCancel
+}
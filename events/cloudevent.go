@@ -0,0 +1,107 @@
+package events
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// CloudEvent is a CNCF CloudEvents v1.0 envelope, as received either in
+// structured mode (the whole request body) or reconstructed from ce-*
+// binary mode headers.
+type CloudEvent struct {
+	Type            string          `json:"type"`
+	Source          string          `json:"source"`
+	ID              string          `json:"id"`
+	Subject         string          `json:"subject,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+
+	// Extensions holds every CE context attribute outside the core spec
+	// attributes above.
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// coreCloudEventAttributes are the CloudEvents v1.0 context attributes
+// that map onto named CloudEvent fields rather than Extensions.
+var coreCloudEventAttributes = map[string]bool{
+	"specversion":     true,
+	"type":            true,
+	"source":          true,
+	"id":              true,
+	"time":            true,
+	"subject":         true,
+	"datacontenttype": true,
+	"dataschema":      true,
+	"data":            true,
+	"data_base64":     true,
+}
+
+const ceHeaderPrefix = "Ce-"
+
+// ParseStructuredCloudEvent decodes a CloudEvents structured-mode JSON body
+// (Content-Type: application/cloudevents+json) into a CloudEvent, keeping
+// every attribute outside the core CE spec as an Extension.
+func ParseStructuredCloudEvent(body []byte) (CloudEvent, error) {
+	var ce CloudEvent
+
+	if err := json.Unmarshal(body, &ce); err != nil {
+		return CloudEvent{}, err
+	}
+
+	var attributes map[string]json.RawMessage
+
+	if err := json.Unmarshal(body, &attributes); err != nil {
+		return CloudEvent{}, err
+	}
+
+	ce.Extensions = make(map[string]interface{})
+
+	for name, value := range attributes {
+		if coreCloudEventAttributes[strings.ToLower(name)] {
+			continue
+		}
+
+		var decoded interface{}
+		if err := json.Unmarshal(value, &decoded); err == nil {
+			ce.Extensions[name] = decoded
+		}
+	}
+
+	return ce, nil
+}
+
+// ParseBinaryCloudEvent reconstructs a CloudEvent from binary-mode ce-*
+// HTTP headers plus the raw request body as Data.
+func ParseBinaryCloudEvent(header http.Header, body []byte) CloudEvent {
+	ce := CloudEvent{
+		Data:       body,
+		Extensions: make(map[string]interface{}),
+	}
+
+	for name, values := range header {
+		if len(values) == 0 || !strings.HasPrefix(name, ceHeaderPrefix) {
+			continue
+		}
+
+		attribute := strings.ToLower(strings.TrimPrefix(name, ceHeaderPrefix))
+		value := values[0]
+
+		switch attribute {
+		case "type":
+			ce.Type = value
+		case "source":
+			ce.Source = value
+		case "id":
+			ce.ID = value
+		case "subject":
+			ce.Subject = value
+		case "datacontenttype":
+			ce.DataContentType = value
+		default:
+			ce.Extensions[attribute] = value
+		}
+	}
+
+	return ce
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseStructuredCloudEventKeepsExtensions(t *testing.T) {
+	body := []byte(`{
+		"specversion": "1.0",
+		"type": "com.example.message",
+		"source": "/chat",
+		"id": "1",
+		"subject": "presence-room",
+		"datacontenttype": "application/json",
+		"data": {"text": "hi"},
+		"traceparent": "00-abc-01"
+	}`)
+
+	ce, err := ParseStructuredCloudEvent(body)
+
+	if err != nil {
+		t.Fatalf("ParseStructuredCloudEvent returned %v", err)
+	}
+
+	if ce.Type != "com.example.message" {
+		t.Errorf("ce.Type == %q, wants %q", ce.Type, "com.example.message")
+	}
+
+	if ce.Source != "/chat" {
+		t.Errorf("ce.Source == %q, wants %q", ce.Source, "/chat")
+	}
+
+	if ce.Subject != "presence-room" {
+		t.Errorf("ce.Subject == %q, wants %q", ce.Subject, "presence-room")
+	}
+
+	if string(ce.Data) != `{"text": "hi"}` {
+		t.Errorf("ce.Data == %s, wants %s", ce.Data, `{"text": "hi"}`)
+	}
+
+	if got := ce.Extensions["traceparent"]; got != "00-abc-01" {
+		t.Errorf("ce.Extensions[\"traceparent\"] == %v, wants %q", got, "00-abc-01")
+	}
+
+	if _, ok := ce.Extensions["type"]; ok {
+		t.Errorf("ce.Extensions should not contain core CE attributes like \"type\"")
+	}
+}
+
+func TestParseStructuredCloudEventInvalidJSON(t *testing.T) {
+	if _, err := ParseStructuredCloudEvent([]byte("not json")); err == nil {
+		t.Errorf("expected an error parsing invalid JSON")
+	}
+}
+
+func TestParseBinaryCloudEventMapsHeadersAndBody(t *testing.T) {
+	header := http.Header{}
+	header.Set("Ce-Type", "com.example.message")
+	header.Set("Ce-Source", "/chat")
+	header.Set("Ce-Id", "1")
+	header.Set("Ce-Subject", "presence-room")
+	header.Set("Ce-Datacontenttype", "application/json")
+	header.Set("Ce-Traceparent", "00-abc-01")
+	header.Set("Content-Type", "application/json")
+
+	body := []byte(`{"text": "hi"}`)
+
+	ce := ParseBinaryCloudEvent(header, body)
+
+	if ce.Type != "com.example.message" {
+		t.Errorf("ce.Type == %q, wants %q", ce.Type, "com.example.message")
+	}
+
+	if ce.Subject != "presence-room" {
+		t.Errorf("ce.Subject == %q, wants %q", ce.Subject, "presence-room")
+	}
+
+	if string(ce.Data) != string(body) {
+		t.Errorf("ce.Data == %s, wants %s", ce.Data, body)
+	}
+
+	if got := ce.Extensions["traceparent"]; got != "00-abc-01" {
+		t.Errorf("ce.Extensions[\"traceparent\"] == %v, wants %q", got, "00-abc-01")
+	}
+
+	if _, ok := ce.Extensions["type"]; ok {
+		t.Errorf("ce.Extensions should not contain core CE attributes like \"type\"")
+	}
+
+	if _, ok := ce.Extensions["content-type"]; ok {
+		t.Errorf("ce.Extensions should ignore non ce-* headers like Content-Type")
+	}
+}
@@ -0,0 +1,17 @@
+package events
+
+import "encoding/json"
+
+// Raw is an event as received from a publisher, before being fanned out
+// to subscribers of its channel.
+type Raw struct {
+	Event   string          `json:"event"`
+	Channel string          `json:"channel"`
+	Data    json.RawMessage `json:"data"`
+
+	// Extensions carries attributes from non-native event sources, such
+	// as a CloudEvent's context attributes, that don't map onto Event,
+	// Channel or Data. When present, it is marshalled unconditionally
+	// under the "extensions" key alongside Data for every subscriber.
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import "time"
+
+// MockSocket is a no-op connection.Socket implementation for use in tests.
+type MockSocket struct{}
+
+// ReadMessage implements connection.Socket.
+func (MockSocket) ReadMessage() (int, []byte, error) { return 0, nil, nil }
+
+// WriteMessage implements connection.Socket.
+func (MockSocket) WriteMessage(messageType int, data []byte) error { return nil }
+
+// SetReadDeadline implements connection.Socket.
+func (MockSocket) SetReadDeadline(deadline time.Time) error { return nil }
+
+// SetWriteDeadline implements connection.Socket.
+func (MockSocket) SetWriteDeadline(deadline time.Time) error { return nil }
+
+// SetPingHandler implements connection.Socket.
+func (MockSocket) SetPingHandler(h func(appData string) error) {}
+
+// SetPongHandler implements connection.Socket.
+func (MockSocket) SetPongHandler(h func(appData string) error) {}
+
+// Close implements connection.Socket.
+func (MockSocket) Close() error { return nil }
@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"nuntius/app"
+	"nuntius/webhooks"
+)
+
+// Storage is the persistence layer nuntius uses to look up configured apps.
+//
+// Its Enqueue/Dequeue methods match webhooks.Queue, so any Storage
+// implementation can be passed straight to webhooks.NewDispatcher as the
+// persistent queue failed webhook deliveries are retried from across
+// restarts, rather than requiring a separate store.
+type Storage interface {
+	GetAppByAppID(appID string) (*app.App, error)
+
+	// Enqueue persists a webhook delivery so it can be picked back up by
+	// Dequeue once its NotBefore has passed.
+	Enqueue(d webhooks.PendingDelivery) error
+
+	// Dequeue removes and returns up to limit webhook deliveries whose
+	// NotBefore has already passed.
+	Dequeue(limit int) ([]webhooks.PendingDelivery, error)
+}
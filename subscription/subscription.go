@@ -1,15 +1,26 @@
 package subscription
 
-import "nuntius/connection"
+import (
+	"time"
+
+	"nuntius/connection"
+)
 
 // Subscription A Channel Subscription
 type Subscription struct {
-	Connection *connection.Connection
-	ID         string
-	Data       string
+	Connection   *connection.Connection
+	ID           string
+	Data         string
+	SubscribedAt time.Time
 }
 
 // New Create a new Subscription
 func New(conn *connection.Connection, data string) *Subscription {
-	return &Subscription{Connection: conn, Data: data}
+	return &Subscription{Connection: conn, Data: data, SubscribedAt: time.Now()}
+}
+
+// Send delivers a raw payload to the subscriber's connection, subject to
+// its outbound backpressure policy.
+func (s *Subscription) Send(data []byte) {
+	s.Connection.Send(data)
 }
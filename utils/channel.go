@@ -0,0 +1,18 @@
+package utils
+
+import "strings"
+
+const (
+	presencePrefix = "presence-"
+	privatePrefix  = "private-"
+)
+
+// IsPresenceChannel reports whether name is a presence channel.
+func IsPresenceChannel(name string) bool {
+	return strings.HasPrefix(name, presencePrefix)
+}
+
+// IsPrivateChannel reports whether name is a private channel.
+func IsPrivateChannel(name string) bool {
+	return strings.HasPrefix(name, privatePrefix)
+}
@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashMAC returns the hex-encoded HMAC-SHA256 digest of data signed with key.
+func HashMAC(data []byte, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
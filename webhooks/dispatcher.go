@@ -0,0 +1,214 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"nuntius/utils"
+)
+
+// maxRecentDeliveries bounds the in-memory history the deliveries admin
+// endpoint can inspect.
+const maxRecentDeliveries = 100
+
+// maxAttempts is how many times a failed delivery is retried before it is
+// dropped.
+const maxAttempts = 5
+
+// job is one queued (config, event) pair awaiting its next delivery
+// attempt.
+type job struct {
+	config  WebhookConfig
+	event   Event
+	attempt int
+}
+
+// Dispatcher delivers Events to an app's configured webhook endpoints
+// through a bounded worker pool, retrying failed deliveries with
+// exponential backoff via a pluggable Queue.
+type Dispatcher struct {
+	appKey string
+	queue  Queue
+	client *http.Client
+	jobs   chan job
+
+	mu         sync.Mutex
+	deliveries []Delivery
+}
+
+// NewDispatcher creates a Dispatcher for the app identified by appKey,
+// starts workers workers, and begins polling queue for due retries.
+func NewDispatcher(appKey string, workers int, queue Queue) *Dispatcher {
+	d := &Dispatcher{
+		appKey: appKey,
+		queue:  queue,
+		client: &http.Client{Timeout: 5 * time.Second},
+		jobs:   make(chan job, 256),
+	}
+
+	for i := 0; i < workers; i++ {
+		go d.worker()
+	}
+
+	go d.retryLoop()
+
+	return d
+}
+
+// Deliver enqueues event for delivery to every config that accepts it.
+// Enqueueing never blocks the caller for longer than it takes to fill the
+// worker pool's job queue.
+func (d *Dispatcher) Deliver(configs []WebhookConfig, event Event) {
+	for _, c := range configs {
+		if !c.accepts(event.Name) {
+			continue
+		}
+
+		select {
+		case d.jobs <- job{config: c, event: event}:
+		default:
+			d.record(Delivery{
+				URL:         c.URL,
+				Event:       event,
+				Error:       "dropped: worker pool saturated",
+				AttemptedAt: time.Now(),
+			})
+		}
+	}
+}
+
+// RecentDeliveries returns up to limit of the most recently attempted
+// deliveries, newest first.
+func (d *Dispatcher) RecentDeliveries(limit int) []Delivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if limit <= 0 || limit > len(d.deliveries) {
+		limit = len(d.deliveries)
+	}
+
+	out := make([]Delivery, limit)
+	for i := 0; i < limit; i++ {
+		out[i] = d.deliveries[len(d.deliveries)-1-i]
+	}
+
+	return out
+}
+
+func (d *Dispatcher) worker() {
+	for j := range d.jobs {
+		d.attempt(j)
+	}
+}
+
+// retryLoop periodically pulls due retries out of the persistent queue
+// and re-attempts them.
+func (d *Dispatcher) retryLoop() {
+	if d.queue == nil {
+		return
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		due, err := d.queue.Dequeue(16)
+
+		if err != nil {
+			continue
+		}
+
+		for _, p := range due {
+			d.attempt(job{config: p.Config, event: p.Event, attempt: p.Attempt})
+		}
+	}
+}
+
+func (d *Dispatcher) attempt(j job) {
+	j.attempt++
+
+	body, err := json.Marshal(j.event)
+
+	if err != nil {
+		d.record(Delivery{URL: j.config.URL, Event: j.event, Attempt: j.attempt, Error: err.Error(), AttemptedAt: time.Now()})
+		return
+	}
+
+	statusCode, err := d.post(j.config, body)
+
+	delivery := Delivery{
+		URL:         j.config.URL,
+		Event:       j.event,
+		Attempt:     j.attempt,
+		StatusCode:  statusCode,
+		AttemptedAt: time.Now(),
+	}
+
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	d.record(delivery)
+
+	if err == nil && statusCode < 300 {
+		return
+	}
+
+	if j.attempt >= maxAttempts || d.queue == nil {
+		return
+	}
+
+	_ = d.queue.Enqueue(PendingDelivery{
+		Config:    j.config,
+		Event:     j.event,
+		Attempt:   j.attempt,
+		NotBefore: time.Now().Add(backoff(j.attempt)),
+	})
+}
+
+func (d *Dispatcher) post(config WebhookConfig, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, config.URL, bytes.NewReader(body))
+
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Nuntius-Key", d.appKey)
+	req.Header.Set("X-Nuntius-Signature", utils.HashMAC(body, []byte(config.Secret)))
+
+	resp, err := d.client.Do(req)
+
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) record(delivery Delivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.deliveries = append(d.deliveries, delivery)
+
+	if len(d.deliveries) > maxRecentDeliveries {
+		d.deliveries = d.deliveries[len(d.deliveries)-maxRecentDeliveries:]
+	}
+}
+
+// backoff returns an exponential backoff duration for the given attempt
+// number, capped at one minute.
+func backoff(attempt int) time.Duration {
+	d := time.Second << uint(attempt)
+
+	if d > time.Minute {
+		return time.Minute
+	}
+
+	return d
+}
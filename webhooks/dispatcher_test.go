@@ -0,0 +1,128 @@
+package webhooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeQueue is an in-memory webhooks.Queue double, standing in for a
+// storage.Storage-backed one in tests.
+type fakeQueue struct {
+	mu      sync.Mutex
+	pending []PendingDelivery
+}
+
+func (q *fakeQueue) Enqueue(d PendingDelivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending = append(q.pending, d)
+
+	return nil
+}
+
+func (q *fakeQueue) Dequeue(limit int) ([]PendingDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var due []PendingDelivery
+	var rest []PendingDelivery
+
+	for _, d := range q.pending {
+		if len(due) < limit && !d.NotBefore.After(time.Now()) {
+			due = append(due, d)
+		} else {
+			rest = append(rest, d)
+		}
+	}
+
+	q.pending = rest
+
+	return due, nil
+}
+
+func TestDispatcherEnqueuesFailedDeliveryOnQueue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	queue := &fakeQueue{}
+	d := NewDispatcher("app-key", 1, queue)
+
+	d.Deliver([]WebhookConfig{{URL: server.URL}}, Event{Name: EventChannelOccupied, Channel: "presence-room"})
+
+	deadline := time.After(time.Second)
+	for {
+		queue.mu.Lock()
+		n := len(queue.pending)
+		queue.mu.Unlock()
+
+		if n == 1 {
+			break
+		}
+
+		select {
+		case <-deadline:
+			t.Fatalf("expected the failed delivery to be enqueued for retry, got %d pending", n)
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	if got := queue.pending[0].Attempt; got != 1 {
+		t.Errorf("pending[0].Attempt == %d, wants %d", got, 1)
+	}
+
+	if !queue.pending[0].NotBefore.After(time.Now()) {
+		t.Errorf("expected NotBefore to be in the future for the next retry")
+	}
+}
+
+func TestDispatcherRetriesFromQueue(t *testing.T) {
+	var attempts int32
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	queue := &fakeQueue{}
+	_ = NewDispatcher("app-key", 1, queue)
+
+	// Hand a due retry straight to the queue retryLoop polls, as if an
+	// earlier failed attempt had already been persisted.
+	queue.Enqueue(PendingDelivery{
+		Config:    WebhookConfig{URL: server.URL},
+		Event:     Event{Name: EventChannelOccupied},
+		Attempt:   1,
+		NotBefore: time.Now(),
+	})
+
+	deadline := time.After(2 * time.Second)
+	for {
+		mu.Lock()
+		n := attempts
+		mu.Unlock()
+
+		if n >= 1 {
+			return
+		}
+
+		select {
+		case <-deadline:
+			t.Fatalf("expected retryLoop to redeliver the queued webhook, got %d attempts", n)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
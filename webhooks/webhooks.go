@@ -0,0 +1,82 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Channel lifecycle and client events that can trigger an outgoing webhook.
+const (
+	EventChannelOccupied = "channel_occupied"
+	EventChannelVacated  = "channel_vacated"
+	EventMemberAdded     = "member_added"
+	EventMemberRemoved   = "member_removed"
+	EventClientEvent     = "client_event"
+)
+
+// WebhookConfig is an operator-configured endpoint nuntius delivers
+// outgoing webhooks to, loaded from an App's YAML config.
+type WebhookConfig struct {
+	URL    string   `yaml:"url"`
+	Secret string   `yaml:"secret"`
+	Filter []string `yaml:"filter"`
+}
+
+// accepts reports whether the config wants to receive events named name.
+// An empty Filter means every event is delivered.
+func (c WebhookConfig) accepts(name string) bool {
+	if len(c.Filter) == 0 {
+		return true
+	}
+
+	for _, f := range c.Filter {
+		if f == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Event is a single outgoing webhook payload.
+type Event struct {
+	Name    string          `json:"name"`
+	Channel string          `json:"channel,omitempty"`
+	Event   string          `json:"event,omitempty"`
+	UserID  string          `json:"user_id,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Time    time.Time       `json:"time"`
+}
+
+// Delivery records one attempt to deliver an Event to a WebhookConfig, for
+// the admin deliveries endpoint.
+type Delivery struct {
+	URL         string    `json:"url"`
+	Event       Event     `json:"event"`
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+// PendingDelivery is a webhook delivery awaiting its next retry attempt.
+type PendingDelivery struct {
+	Config    WebhookConfig
+	Event     Event
+	Attempt   int
+	NotBefore time.Time
+}
+
+// Queue is a pluggable persistent store for retrying webhook deliveries
+// across restarts. storage.Storage's method set satisfies Queue, so
+// NewDispatcher is typically handed the same storage.Storage
+// implementation the rest of nuntius uses rather than a separate store.
+type Queue interface {
+	// Enqueue persists d so it can be picked back up by Dequeue once its
+	// NotBefore has passed.
+	Enqueue(d PendingDelivery) error
+
+	// Dequeue removes and returns up to limit entries whose NotBefore has
+	// already passed.
+	Dequeue(limit int) ([]PendingDelivery, error)
+}